@@ -0,0 +1,89 @@
+// Copyright 2016 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cc
+
+import (
+	"android/soong/cc/config"
+)
+
+// BaseCompilerProperties are the module properties common to every cc
+// compiler variant. config.SdclangProperties is embedded here, rather than
+// on individual module types, so every cc_* module picks up the "sdclang"
+// property the same way.
+type BaseCompilerProperties struct {
+	// Cflags to use when compiling this module's source.
+	Cflags []string
+
+	config.SdclangProperties
+	config.ToolchainProperties
+}
+
+// baseCompiler holds a cc module's compiler properties. Its methods below
+// resolve, per module, the SDClang selection, toolchain, compiler binary and
+// cflags that module's compile action is built with.
+type baseCompiler struct {
+	Properties BaseCompilerProperties
+}
+
+// useSDClang reports whether this module builds with SDClang, honoring its
+// own "sdclang.enabled" override ahead of the product-wide default and the
+// module allowlist/blocklist.
+func (compiler *baseCompiler) useSDClang(moduleName string) bool {
+	return config.SDClangEnabledForModule(moduleName, compiler.Properties.Sdclang.Enabled)
+}
+
+// toolchain resolves the ToolchainProvider this module builds against,
+// honoring its own "toolchain" property ahead of its SDClang selection.
+func (compiler *baseCompiler) toolchain(moduleName string) config.ToolchainProvider {
+	return config.ToolchainForModule(moduleName, compiler.Properties.Toolchain, compiler.Properties.Sdclang.Enabled)
+}
+
+// clangBin returns the ninja variable reference, or for a module that sets
+// its "toolchain" property (even to "aosp"), the bin directory of the
+// resolved ToolchainProvider, for the compiler binary to invoke for this
+// module.
+func (compiler *baseCompiler) clangBin(moduleName string) string {
+	if compiler.Properties.Toolchain != nil {
+		return config.ClangBinForToolchain(compiler.toolchain(moduleName))
+	}
+	return config.ClangBinForModule(compiler.useSDClang(moduleName))
+}
+
+// asanLibDir returns this module's ASan runtime library directory, resolved
+// from its ToolchainProvider instead of the global ${ClangAsanLibDir}, so a
+// module on a non-default toolchain gets that toolchain's ASan runtime.
+func (compiler *baseCompiler) asanLibDir(moduleName string) string {
+	return config.ClangAsanLibDirForToolchain(compiler.toolchain(moduleName))
+}
+
+// rsIncludePath returns this module's libclang RS header include path,
+// resolved from its ToolchainProvider instead of the global
+// ${RSIncludePath}, so a module on a non-default toolchain gets that
+// toolchain's RS headers.
+func (compiler *baseCompiler) rsIncludePath(moduleName string) string {
+	return config.RSIncludePathForToolchain(compiler.toolchain(moduleName))
+}
+
+// compilerFlags returns the cflags to compile this module with: the
+// caller-selected cflags and the module's own Cflags property, with this
+// module's "sdclang" extra/exclude overrides applied on top when it builds
+// with SDClang.
+func (compiler *baseCompiler) compilerFlags(moduleName string, cflags []string) []string {
+	cflags = append(cflags, compiler.Properties.Cflags...)
+	if compiler.useSDClang(moduleName) {
+		cflags = config.SDClangCflagsForModule(cflags, compiler.Properties.SdclangProperties)
+	}
+	return cflags
+}
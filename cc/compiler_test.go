@@ -0,0 +1,92 @@
+// Copyright 2016 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cc
+
+import (
+	"testing"
+
+	"android/soong/cc/config"
+)
+
+func TestBaseCompilerUseSDClangHonorsModuleOverride(t *testing.T) {
+	enabled := true
+	compiler := &baseCompiler{}
+	compiler.Properties.Sdclang.Enabled = &enabled
+
+	if !compiler.useSDClang("libfoo") {
+		t.Error("expected sdclang.enabled=true on the module to select SDClang")
+	}
+}
+
+func TestBaseCompilerClangBinFollowsUseSDClang(t *testing.T) {
+	enabled := true
+	compiler := &baseCompiler{}
+	compiler.Properties.Sdclang.Enabled = &enabled
+
+	if got, want := compiler.clangBin("libfoo"), "${SDClangBin}"; got != want {
+		t.Errorf("clangBin() = %q, want %q", got, want)
+	}
+}
+
+func TestBaseCompilerClangBinPrefersExplicitToolchain(t *testing.T) {
+	name := "aosp"
+	compiler := &baseCompiler{}
+	compiler.Properties.Toolchain = &name
+
+	if got, want := compiler.clangBin("libfoo"), config.ClangBinForToolchain(config.AospClangToolchain{}); got != want {
+		t.Errorf("clangBin() = %q, want %q", got, want)
+	}
+}
+
+func TestBaseCompilerCompilerFlagsAppliesModuleOverrides(t *testing.T) {
+	enabled := true
+	compiler := &baseCompiler{}
+	compiler.Properties.Cflags = []string{"-Wall"}
+	compiler.Properties.Sdclang.Enabled = &enabled
+	compiler.Properties.Sdclang.Exclude_cflags = []string{"-Wall"}
+	compiler.Properties.Sdclang.Extra_cflags = []string{"-flto"}
+
+	got := compiler.compilerFlags("libfoo", nil)
+
+	want := []string{"-flto"}
+	if len(got) != len(want) {
+		t.Fatalf("compilerFlags() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("compilerFlags() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBaseCompilerCompilerFlagsNoOpWithoutSDClang(t *testing.T) {
+	disabled := false
+	compiler := &baseCompiler{}
+	compiler.Properties.Cflags = []string{"-Wall"}
+	compiler.Properties.Sdclang.Enabled = &disabled
+	compiler.Properties.Sdclang.Extra_cflags = []string{"-flto"}
+
+	got := compiler.compilerFlags("libfoo", nil)
+
+	want := []string{"-Wall"}
+	if len(got) != len(want) {
+		t.Fatalf("compilerFlags() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("compilerFlags() = %v, want %v", got, want)
+		}
+	}
+}
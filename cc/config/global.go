@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -82,9 +84,47 @@ var (
 	ClangDefaultBase         = "prebuilts/clang/host"
 	ClangDefaultVersion      = "clang-4053586"
 	ClangDefaultShortVersion = "5.0"
-	SDClang                   = false
+	SDClang                  = false
+
+	// Module names listed here are built with SDClang even when the
+	// product-wide default is off, unless the module's own "sdclang.enabled"
+	// property says otherwise. Populated from SDCLANG_MODULE_ALLOWLIST in
+	// the SDClang config.
+	sdclangModuleAllowlist []string
+
+	// Module names listed here are not built with SDClang, unless the
+	// module's own "sdclang.enabled" property says otherwise. Populated from
+	// SDCLANG_MODULE_BLOCKLIST in the SDClang config. Blocklist takes
+	// precedence over the allowlist.
+	sdclangModuleBlocklist []string
+
+	// The resolved SDClang toolchain, set by setSdclangVars when an SDClang
+	// path is configured and either SDClang is the product-wide default or
+	// the module allowlist is non-empty. Backs SDClangToolchain.
+	sdclangToolchainBin     string
+	sdclangToolchainVersion string
+	sdclangToolchainLibDir  string
+	sdclangToolchainFlags   string
 )
 
+// SdclangProperties is embedded by cc modules to let them opt into or out of
+// SDClang independently of the global SDClang setting.
+type SdclangProperties struct {
+	Sdclang struct {
+		// Enabled overrides the global and allowlist/blocklist SDClang
+		// selection for this module when set.
+		Enabled *bool
+
+		// Extra_cflags are appended to the module's cflags when it is built
+		// with SDClang. See SDClangCflagsForModule.
+		Extra_cflags []string
+
+		// Exclude_cflags are removed from the module's cflags when it is
+		// built with SDClang. See SDClangCflagsForModule.
+		Exclude_cflags []string
+	}
+}
+
 var pctx = android.NewPackageContext("android/soong/cc/config")
 
 func init() {
@@ -177,98 +217,479 @@ func init() {
 	})
 
 	setSdclangVars()
+
+	android.RegisterSingletonType("sdclang-config-errors", sdclangConfigErrorsSingletonFactory)
 }
 
-func setSdclangVars() {
-	sdclangPath := ""
-	sdclangAEFlag := ""
-	sdclangFlags := ""
+// sdclangConfigErrorsSingletonFactory registers a singleton whose only job
+// is to forward sdclangConfigErrors through a real android.SingletonContext,
+// since none exists yet when setSdclangVars runs from init().
+func sdclangConfigErrorsSingletonFactory() android.Singleton {
+	return &sdclangConfigErrorsSingleton{}
+}
 
-	product := os.Getenv("TARGET_PRODUCT")
-	androidRoot := os.Getenv("ANDROID_BUILD_TOP")
-	aeConfigPath := os.Getenv("SDCLANG_AE_CONFIG")
-	sdclangConfigPath := os.Getenv("SDCLANG_CONFIG")
-
-	type sdclangAEConfig struct {
-		SDCLANG_AE_FLAG string
-	}
-
-	// Load AE config file and set AE flag
-	aeConfigFile := path.Join(androidRoot, aeConfigPath)
-	if file, err := os.Open(aeConfigFile); err == nil {
-		decoder := json.NewDecoder(file)
-		aeConfig := sdclangAEConfig{}
-		if err := decoder.Decode(&aeConfig); err == nil {
-			sdclangAEFlag = aeConfig.SDCLANG_AE_FLAG
-		} else {
-			panic(err)
+type sdclangConfigErrorsSingleton struct{}
+
+func (sdclangConfigErrorsSingleton) GenerateBuildActions(ctx android.SingletonContext) {
+	ReportSdclangConfigErrors(ctx)
+}
+
+// SDClangProductConfig is the per-product block of an SDClang config file.
+type SDClangProductConfig struct {
+	SDClang      bool   `json:"SDCLANG"`
+	SDClangPath  string `json:"SDCLANG_PATH"`
+	SDClangFlags string `json:"SDCLANG_FLAGS"`
+}
+
+// SDClangConfig is the typed, merged representation of one or more SDClang
+// JSON config files. setSdclangVars loads a stack of these (SDCLANG_CONFIG
+// plus any vendor overlays) and merges them into one before using it.
+type SDClangConfig struct {
+	Path     string                          `json:"Path"`
+	AEFlag   string                          `json:"AEFlag"`
+	Flags    string                          `json:"Flags"`
+	Version  string                          `json:"Version"`
+	LibDir   string                          `json:"LibDir"`
+	Products map[string]SDClangProductConfig `json:"Products"`
+
+	ModuleAllowlist []string `json:"SDCLANG_MODULE_ALLOWLIST"`
+	ModuleBlocklist []string `json:"SDCLANG_MODULE_BLOCKLIST"`
+}
+
+// sdclangConfigErrors accumulates problems found while loading and
+// validating the SDClang config. They can't be reported immediately because
+// setSdclangVars runs from init(), before an android.Config exists to report
+// them through. ReportSdclangConfigErrors forwards them once one does, so
+// soong_build can surface every misconfiguration in one pass instead of
+// aborting on the first bad field.
+var sdclangConfigErrors []error
+
+// ReportSdclangConfigErrors reports any errors accumulated while loading the
+// SDClang config through ctx, in place of the panics setSdclangVars used to
+// throw directly.
+func ReportSdclangConfigErrors(ctx android.SingletonContext) {
+	for _, err := range sdclangConfigErrors {
+		ctx.Errorf("%s", err)
+	}
+}
+
+// validate checks that cfg has everything required to build product with
+// SDClang, returning one error per problem found rather than stopping at the
+// first one.
+func (cfg *SDClangConfig) validate(product string) (errs []error) {
+	p, ok := cfg.Products[product]
+	if !ok || !p.SDClang {
+		return nil
+	}
+	if p.SDClangPath == "" && cfg.Path == "" && os.Getenv("SDCLANG_PATH") == "" {
+		errs = append(errs, fmt.Errorf("SDCLANG_PATH is required for product %q when SDCLANG is true", product))
+	}
+	return errs
+}
+
+// loadSdclangConfigFile reads and schema-checks a single SDClang config
+// file. A missing file is not an error; the caller decides whether that
+// matters.
+func loadSdclangConfigFile(configPath string) (*SDClangConfig, error) {
+	file, err := os.Open(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
 		}
+		return nil, err
+	}
+	defer file.Close()
+
+	cfg := &SDClangConfig{}
+	if err := json.NewDecoder(file).Decode(cfg); err != nil {
+		return nil, fmt.Errorf("invalid SDClang config %s: %s", configPath, err)
 	}
+	return cfg, nil
+}
 
-	// Load SD Clang config file and set SD Clang variables
-	sdclangConfigFile := path.Join(androidRoot, sdclangConfigPath)
-	var sdclangConfig interface{}
-	if file, err := os.Open(sdclangConfigFile); err == nil {
-		decoder := json.NewDecoder(file)
-                // Parse the config file
-		if err := decoder.Decode(&sdclangConfig); err == nil {
-			config := sdclangConfig.(map[string]interface{})
-			// Retrieve the device specific block if it exists in the config file
-			if dev, ok := config[product]; ok {
-				devConfig := dev.(map[string]interface{})
-				// Check if SDCLANG is set
-				if _, ok := devConfig["SDCLANG"]; ok {
-					// If SDCLANG is set to true, set other variables accordingly
-					if sdclang := devConfig["SDCLANG"].(bool); sdclang {
-						SDClang = true
-						// SDCLANG_PATH is required if SDCLANG is set to true
-						if _, ok := devConfig["SDCLANG_PATH"]; ok {
-							sdclangPath = devConfig["SDCLANG_PATH"].(string)
-						} else {
-							panic("SDCLANG_PATH is required if SDCLANG is true")
-						}
-						// SDCLANG_FLAGS is optional
-						if _, ok := devConfig["SDCLANG_FLAGS"]; ok {
-							sdclangFlags = devConfig["SDCLANG_FLAGS"].(string)
-						}
-					}
-				}
+// mergeSdclangConfig merges src into dst. Scalar fields in src win when set;
+// products and the module allowlist/blocklist are accumulated, so later
+// files in the stack (vendor overlays in particular) can add to or override
+// earlier ones without having to repeat them.
+func mergeSdclangConfig(dst, src *SDClangConfig) {
+	if src.Path != "" {
+		dst.Path = src.Path
+	}
+	if src.AEFlag != "" {
+		dst.AEFlag = src.AEFlag
+	}
+	if src.Flags != "" {
+		dst.Flags = src.Flags
+	}
+	if src.Version != "" {
+		dst.Version = src.Version
+	}
+	if src.LibDir != "" {
+		dst.LibDir = src.LibDir
+	}
+	if dst.Products == nil {
+		dst.Products = make(map[string]SDClangProductConfig)
+	}
+	for product, productConfig := range src.Products {
+		dst.Products[product] = productConfig
+	}
+	dst.ModuleAllowlist = append(dst.ModuleAllowlist, src.ModuleAllowlist...)
+	dst.ModuleBlocklist = append(dst.ModuleBlocklist, src.ModuleBlocklist...)
+}
+
+// loadSdclangConfigs loads and merges the stack of SDClang config files:
+// each colon-separated entry of SDCLANG_CONFIG, in order, followed by any
+// vendor/*/sdclang.json overlays discovered automatically, sorted by path
+// so the merge order is deterministic. Overlays are applied last and so can
+// override anything set by SDCLANG_CONFIG.
+func loadSdclangConfigs(androidRoot string) (*SDClangConfig, []error) {
+	merged := &SDClangConfig{}
+	var errs []error
+
+	var configPaths []string
+	for _, p := range strings.Split(os.Getenv("SDCLANG_CONFIG"), ":") {
+		if p != "" {
+			configPaths = append(configPaths, path.Join(androidRoot, p))
+		}
+	}
+
+	overlays, err := filepath.Glob(path.Join(androidRoot, "vendor/*/sdclang.json"))
+	if err != nil {
+		errs = append(errs, err)
+	}
+	sort.Strings(overlays)
+	configPaths = append(configPaths, overlays...)
+
+	for _, configPath := range configPaths {
+		cfg, err := loadSdclangConfigFile(configPath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if cfg != nil {
+			mergeSdclangConfig(merged, cfg)
+		}
+	}
+
+	return merged, errs
+}
+
+func setSdclangVars() {
+	product := os.Getenv("TARGET_PRODUCT")
+	androidRoot := os.Getenv("ANDROID_BUILD_TOP")
+
+	sdclangAEFlag := ""
+	if aeConfigPath := os.Getenv("SDCLANG_AE_CONFIG"); aeConfigPath != "" {
+		type sdclangAEConfig struct {
+			SDCLANG_AE_FLAG string
+		}
+		if file, err := os.Open(path.Join(androidRoot, aeConfigPath)); err == nil {
+			defer file.Close()
+			aeConfig := sdclangAEConfig{}
+			if err := json.NewDecoder(file).Decode(&aeConfig); err == nil {
+				sdclangAEFlag = aeConfig.SDCLANG_AE_FLAG
+			} else {
+				sdclangConfigErrors = append(sdclangConfigErrors, fmt.Errorf("invalid SDClang AE config %s: %s", aeConfigPath, err))
 			}
-		} else {
-			panic(err)
 		}
-	} else {
-		fmt.Println(err)
 	}
 
-	// Override SDCLANG if the varialbe is set in the environment
+	merged, errs := loadSdclangConfigs(androidRoot)
+	sdclangModuleAllowlist = merged.ModuleAllowlist
+	sdclangModuleBlocklist = merged.ModuleBlocklist
+	errs = append(errs, merged.validate(product)...)
+	sdclangConfigErrors = append(sdclangConfigErrors, errs...)
+	if len(errs) > 0 {
+		return
+	}
+
+	if sdclangAEFlag == "" {
+		sdclangAEFlag = merged.AEFlag
+	}
+
+	productConfig := merged.Products[product]
+	SDClang = productConfig.SDClang
+
+	// Override SDCLANG if the variable is set in the environment. This can
+	// turn SDClang on even for a product with no entry in the merged
+	// config, so the toolchain setup below must not assume one exists.
 	if sdclang := os.Getenv("SDCLANG"); sdclang != "" {
 		if override, err := strconv.ParseBool(sdclang); err == nil {
 			SDClang = override
 		}
 	}
 
-	if SDClang {
-		// Sanity check SDCLANG_PATH
-		if envPath := os.Getenv("SDCLANG_PATH"); sdclangPath == "" && envPath == "" {
-			panic("SDCLANG_PATH can not be empty if SDCLANG is true")
+	// Modules can be switched to SDClang individually through the
+	// allowlist or their own "sdclang" property even on a product whose
+	// own SDClang default is off, so the toolchain must be resolved and
+	// registered whenever anything could reference it, not only when
+	// SDClang is the product-wide default.
+	if !SDClang && len(merged.ModuleAllowlist) == 0 {
+		return
+	}
+
+	sdclangPath := productConfig.SDClangPath
+	if sdclangPath == "" {
+		sdclangPath = merged.Path
+	}
+	sdclangFlags := productConfig.SDClangFlags
+	if sdclangFlags == "" {
+		sdclangFlags = merged.Flags
+	}
+	if sdclangPath == "" && os.Getenv("SDCLANG_PATH") == "" {
+		if SDClang {
+			sdclangConfigErrors = append(sdclangConfigErrors, fmt.Errorf("SDCLANG_PATH is required for product %q when SDCLANG is true", product))
 		}
+		// Otherwise SDClang was only referenced through the module
+		// allowlist with no path configured anywhere; allowlisted modules
+		// simply won't have an SDClang toolchain to build against, which
+		// SDClangEnabledForModule/ToolchainForModule's callers need to
+		// handle, but it's not a reason to fail the rest of the build.
+		return
+	}
 
-		// Override SDCLANG_PATH if the variable is set in the environment
-		pctx.VariableFunc("SDClangBin", func(config interface{}) (string, error) {
-			if override := config.(android.Config).Getenv("SDCLANG_PATH"); override != "" {
-				return override, nil
-			}
-			return sdclangPath, nil
-		})
-		// Override SDCLANG_COMMON_FLAGS if the variable is set in the environment
-		pctx.VariableFunc("SDClangFlags", func(config interface{}) (string, error) {
-			if override := config.(android.Config).Getenv("SDCLANG_COMMON_FLAGS"); override != "" {
-				return override, nil
+	sdclangToolchainBin = sdclangPath
+	sdclangToolchainVersion = merged.Version
+	sdclangToolchainLibDir = merged.LibDir
+	sdclangToolchainFlags = sdclangAEFlag + " " + sdclangFlags
+
+	// Override SDCLANG_PATH if the variable is set in the environment
+	pctx.VariableFunc("SDClangBin", func(config interface{}) (string, error) {
+		if override := config.(android.Config).Getenv("SDCLANG_PATH"); override != "" {
+			return override, nil
+		}
+		return sdclangPath, nil
+	})
+	// Override SDCLANG_COMMON_FLAGS if the variable is set in the environment
+	pctx.VariableFunc("SDClangFlags", func(config interface{}) (string, error) {
+		if override := config.(android.Config).Getenv("SDCLANG_COMMON_FLAGS"); override != "" {
+			return override, nil
+		}
+		return sdclangAEFlag + " " + sdclangFlags, nil
+	})
+}
+
+// SDClangEnabledForModule reports whether the named module should be built
+// with SDClang rather than AOSP Clang. moduleOverride is the module's own
+// "sdclang.enabled" property, or nil if the module doesn't set it. The
+// blocklist always wins over the allowlist, and an explicit module override
+// always wins over both.
+func SDClangEnabledForModule(moduleName string, moduleOverride *bool) bool {
+	if moduleOverride != nil {
+		return *moduleOverride
+	}
+	for _, blocked := range sdclangModuleBlocklist {
+		if blocked == moduleName {
+			return false
+		}
+	}
+	for _, allowed := range sdclangModuleAllowlist {
+		if allowed == moduleName {
+			return true
+		}
+	}
+	return SDClang
+}
+
+// ClangBinForModule returns the ${ClangBin}/${SDClangBin} ninja variable
+// reference to use for a module, given whether the module resolved to
+// SDClang via SDClangEnabledForModule.
+func ClangBinForModule(useSDClang bool) string {
+	if useSDClang {
+		return "${SDClangBin}"
+	}
+	return "${ClangBin}"
+}
+
+// SDClangCflagsForModule applies a module's own "sdclang" property overrides
+// on top of cflags already selected for a module building with SDClang:
+// props.Sdclang.Exclude_cflags are stripped and props.Sdclang.Extra_cflags
+// are appended. Callers only need this when SDClangEnabledForModule returned
+// true for the module; Enabled isn't consulted here, so the overrides are
+// applied whenever it is called.
+func SDClangCflagsForModule(cflags []string, props SdclangProperties) []string {
+	if len(props.Sdclang.Exclude_cflags) == 0 && len(props.Sdclang.Extra_cflags) == 0 {
+		return cflags
+	}
+	result := make([]string, 0, len(cflags)+len(props.Sdclang.Extra_cflags))
+	for _, flag := range cflags {
+		excluded := false
+		for _, exclude := range props.Sdclang.Exclude_cflags {
+			if flag == exclude {
+				excluded = true
+				break
 			}
-			return sdclangAEFlag + " " + sdclangFlags, nil
-		})
+		}
+		if !excluded {
+			result = append(result, flag)
+		}
 	}
+	return append(result, props.Sdclang.Extra_cflags...)
+}
+
+// ToolchainProvider is a selectable Clang prebuilt. Modules pick one by name
+// via the "toolchain" property; AospClangToolchain is used when a module
+// doesn't set one (or SDClangToolchain, if the module builds with SDClang).
+type ToolchainProvider interface {
+	// ClangBase is the root of the Clang install. For providers that follow
+	// the prebuilts/clang layout, it's joined with ${HostPrebuiltTag} and
+	// ClangVersion to form the toolchain's path; see ClangPathForToolchain.
+	ClangBase() string
+	ClangVersion() string
+	ClangShortVersion() string
+	ClangExtraCflags() []string
+}
+
+// AospClangToolchain is the default, AOSP-shipped Clang prebuilt, overridable
+// with the same LLVM_PREBUILTS_*/LLVM_RELEASE_VERSION environment variables
+// the package-level ClangBase/ClangVersion/ClangShortVersion honor.
+type AospClangToolchain struct{}
+
+func (AospClangToolchain) ClangBase() string {
+	if override := os.Getenv("LLVM_PREBUILTS_BASE"); override != "" {
+		return override
+	}
+	return ClangDefaultBase
+}
+
+func (AospClangToolchain) ClangVersion() string {
+	if override := os.Getenv("LLVM_PREBUILTS_VERSION"); override != "" {
+		return override
+	}
+	return ClangDefaultVersion
+}
+
+func (AospClangToolchain) ClangShortVersion() string {
+	if override := os.Getenv("LLVM_RELEASE_VERSION"); override != "" {
+		return override
+	}
+	return ClangDefaultShortVersion
+}
+
+func (AospClangToolchain) ClangExtraCflags() []string { return nil }
+
+// SDClangToolchain is the SDClang prebuilt resolved for the current product
+// by setSdclangVars. Unlike AospClangToolchain, an SDClang config hands out
+// a single resolved bin directory rather than a base+version pair, so
+// ClangBase here is that bin directory itself and ClangVersion doubles as
+// its short version.
+type SDClangToolchain struct{}
+
+func (SDClangToolchain) ClangBase() string {
+	if override := os.Getenv("SDCLANG_PATH"); override != "" {
+		return override
+	}
+	return sdclangToolchainBin
+}
+
+func (SDClangToolchain) ClangVersion() string      { return sdclangToolchainVersion }
+func (SDClangToolchain) ClangShortVersion() string { return sdclangToolchainVersion }
+
+func (SDClangToolchain) ClangExtraCflags() []string {
+	if override := os.Getenv("SDCLANG_COMMON_FLAGS"); override != "" {
+		return strings.Fields(override)
+	}
+	return strings.Fields(sdclangToolchainFlags)
+}
+
+// CustomClangToolchain is a one-off Clang prebuilt a module can point at
+// directly, to A/B test a new Clang version on that module without
+// registering it globally.
+type CustomClangToolchain struct {
+	Base, Version, ShortVersion string
+	ExtraCflags                 []string
+}
+
+func (c CustomClangToolchain) ClangBase() string          { return c.Base }
+func (c CustomClangToolchain) ClangVersion() string       { return c.Version }
+func (c CustomClangToolchain) ClangShortVersion() string  { return c.ShortVersion }
+func (c CustomClangToolchain) ClangExtraCflags() []string { return c.ExtraCflags }
+
+var toolchainProviders = map[string]ToolchainProvider{
+	"aosp":    AospClangToolchain{},
+	"sdclang": SDClangToolchain{},
+}
+
+// RegisterToolchainProvider makes a ToolchainProvider selectable by name via
+// a module's "toolchain" property.
+func RegisterToolchainProvider(name string, provider ToolchainProvider) {
+	toolchainProviders[name] = provider
+}
+
+// ToolchainProviderByName looks up a registered ToolchainProvider, returning
+// ok=false if name hasn't been registered.
+func ToolchainProviderByName(name string) (provider ToolchainProvider, ok bool) {
+	provider, ok = toolchainProviders[name]
+	return provider, ok
+}
+
+// ToolchainProperties lets a cc module select a non-default ToolchainProvider
+// by name, e.g. to try a new Clang version on a single module without
+// forking the tree.
+type ToolchainProperties struct {
+	// Toolchain names a ToolchainProvider registered with
+	// RegisterToolchainProvider. Defaults to AospClangToolchain, or
+	// SDClangToolchain if the module builds with SDClang.
+	Toolchain *string
+}
+
+// ToolchainForModule resolves a module's "toolchain" property to a
+// ToolchainProvider, falling back to SDClangEnabledForModule's result when
+// the module doesn't select one explicitly.
+func ToolchainForModule(moduleName string, toolchainProp *string, sdclangOverride *bool) ToolchainProvider {
+	if toolchainProp != nil {
+		if provider, ok := ToolchainProviderByName(*toolchainProp); ok {
+			return provider
+		}
+	}
+	if SDClangEnabledForModule(moduleName, sdclangOverride) {
+		return SDClangToolchain{}
+	}
+	return AospClangToolchain{}
+}
+
+// ClangPathForToolchain returns the toolchain install path of t: the
+// directory containing its "bin", "lib64", etc, in the same shape as the
+// package-level ${ClangPath} static variable. SDCLANG_PATH is, like
+// ${ClangBin}, already a bin directory rather than a base+version pair, so
+// SDClangToolchain strips the trailing "bin" component instead of joining
+// one on.
+func ClangPathForToolchain(t ToolchainProvider) string {
+	if sdclang, ok := t.(SDClangToolchain); ok {
+		return strings.TrimSuffix(sdclang.ClangBase(), "/bin")
+	}
+	return fmt.Sprintf("%s/${HostPrebuiltTag}/%s", t.ClangBase(), t.ClangVersion())
+}
+
+// ClangBinForToolchain returns the bin directory of t, replacing the
+// ${ClangBin}/${SDClangBin} static variables for modules that select a
+// non-default toolchain. For SDClangToolchain this is ClangBase() itself
+// (matching ClangBinForModule's "${SDClangBin}"), not a "bin" joined onto
+// ClangPathForToolchain's already-stripped result.
+func ClangBinForToolchain(t ToolchainProvider) string {
+	if sdclang, ok := t.(SDClangToolchain); ok {
+		return sdclang.ClangBase()
+	}
+	return ClangPathForToolchain(t) + "/bin"
+}
+
+// ClangAsanLibDirForToolchain returns the ASan runtime library directory of
+// t, replacing the hard-coded ${ClangPath}-based ClangAsanLibDir static
+// variable for modules that select a non-default toolchain. SDClangToolchain
+// honors an explicit LibDir from its config instead of deriving the path,
+// since vendor SDClang prebuilts don't always follow the AOSP layout.
+func ClangAsanLibDirForToolchain(t ToolchainProvider) string {
+	if _, ok := t.(SDClangToolchain); ok && sdclangToolchainLibDir != "" {
+		return sdclangToolchainLibDir
+	}
+	return fmt.Sprintf("%s/lib64/clang/%s/lib/linux", ClangPathForToolchain(t), t.ClangShortVersion())
+}
+
+// RSIncludePathForToolchain returns the libclang RS header include path of
+// t, replacing the hard-coded ${RSIncludePath} static variable for modules
+// that select a non-default toolchain.
+func RSIncludePathForToolchain(t ToolchainProvider) string {
+	return fmt.Sprintf("%s/lib64/clang/%s/include", ClangPathForToolchain(t), t.ClangShortVersion())
 }
 
 var HostPrebuiltTag = pctx.VariableConfigMethod("HostPrebuiltTag", android.Config.PrebuiltOS)
@@ -0,0 +1,263 @@
+// Copyright 2016 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestConfig(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(p, []byte(contents), 0640); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLoadSdclangConfigFileMissingFile(t *testing.T) {
+	cfg, err := loadSdclangConfigFile(filepath.Join(t.TempDir(), "does_not_exist.json"))
+	if err != nil {
+		t.Fatalf("missing config file should not be an error, got %s", err)
+	}
+	if cfg != nil {
+		t.Fatalf("expected a nil config for a missing file, got %+v", cfg)
+	}
+}
+
+func TestLoadSdclangConfigFileWrongType(t *testing.T) {
+	dir := t.TempDir()
+	p := writeTestConfig(t, dir, "sdclang.json", `{"Products": {"foo": {"SDCLANG": "not-a-bool"}}}`)
+
+	if _, err := loadSdclangConfigFile(p); err == nil {
+		t.Fatal("expected an error decoding a product with a non-bool SDCLANG field")
+	}
+}
+
+func TestSDClangConfigValidateMissingPath(t *testing.T) {
+	os.Unsetenv("SDCLANG_PATH")
+	cfg := &SDClangConfig{
+		Products: map[string]SDClangProductConfig{
+			"foo": {SDClang: true},
+		},
+	}
+
+	errs := cfg.validate("foo")
+	if len(errs) != 1 {
+		t.Fatalf("expected exactly one error for a missing SDCLANG_PATH, got %v", errs)
+	}
+}
+
+func TestSDClangConfigValidateProductFallback(t *testing.T) {
+	os.Unsetenv("SDCLANG_PATH")
+	cfg := &SDClangConfig{
+		Path: "/default/sdclang/bin",
+		Products: map[string]SDClangProductConfig{
+			"foo": {SDClang: true},
+		},
+	}
+
+	if errs := cfg.validate("foo"); len(errs) != 0 {
+		t.Fatalf("expected the top-level Path to satisfy product %q, got %v", "foo", errs)
+	}
+}
+
+func TestSDClangConfigValidateIgnoresOtherProducts(t *testing.T) {
+	cfg := &SDClangConfig{
+		Products: map[string]SDClangProductConfig{
+			"foo": {SDClang: true, SDClangPath: "/foo/bin"},
+		},
+	}
+
+	if errs := cfg.validate("bar"); len(errs) != 0 {
+		t.Fatalf("expected no errors for a product with no config, got %v", errs)
+	}
+}
+
+func TestMergeSdclangConfigOverlayPrecedence(t *testing.T) {
+	dst := &SDClangConfig{
+		Path: "/base/bin",
+		Products: map[string]SDClangProductConfig{
+			"foo": {SDClang: true, SDClangPath: "/base/foo/bin"},
+		},
+		ModuleAllowlist: []string{"libfoo"},
+	}
+	overlay := &SDClangConfig{
+		Path: "/overlay/bin",
+		Products: map[string]SDClangProductConfig{
+			"bar": {SDClang: true},
+		},
+		ModuleAllowlist: []string{"libbar"},
+	}
+
+	mergeSdclangConfig(dst, overlay)
+
+	if dst.Path != "/overlay/bin" {
+		t.Errorf("expected the overlay's Path to win, got %q", dst.Path)
+	}
+	if _, ok := dst.Products["foo"]; !ok {
+		t.Errorf("expected the base product %q to survive the merge", "foo")
+	}
+	if _, ok := dst.Products["bar"]; !ok {
+		t.Errorf("expected the overlay product %q to be added by the merge", "bar")
+	}
+	wantAllowlist := []string{"libfoo", "libbar"}
+	if len(dst.ModuleAllowlist) != len(wantAllowlist) {
+		t.Fatalf("expected allowlist %v, got %v", wantAllowlist, dst.ModuleAllowlist)
+	}
+	for i, name := range wantAllowlist {
+		if dst.ModuleAllowlist[i] != name {
+			t.Errorf("expected allowlist[%d] = %q, got %q", i, name, dst.ModuleAllowlist[i])
+		}
+	}
+}
+
+func TestLoadSdclangConfigsOverlayPrecedence(t *testing.T) {
+	root := t.TempDir()
+	writeTestConfig(t, root, "sdclang.json", `{
+		"Path": "/base/bin",
+		"Products": {"foo": {"SDCLANG": true}}
+	}`)
+	writeTestConfig(t, root, "vendor/acme/sdclang.json", `{
+		"Path": "/vendor/acme/bin",
+		"SDCLANG_MODULE_ALLOWLIST": ["libacme"]
+	}`)
+
+	os.Setenv("SDCLANG_CONFIG", "sdclang.json")
+	defer os.Unsetenv("SDCLANG_CONFIG")
+
+	merged, errs := loadSdclangConfigs(root)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if merged.Path != "/vendor/acme/bin" {
+		t.Errorf("expected the vendor overlay to override Path, got %q", merged.Path)
+	}
+	if _, ok := merged.Products["foo"]; !ok {
+		t.Errorf("expected the base product %q to survive the merge", "foo")
+	}
+	if len(merged.ModuleAllowlist) != 1 || merged.ModuleAllowlist[0] != "libacme" {
+		t.Errorf("expected the vendor overlay's allowlist to be merged in, got %v", merged.ModuleAllowlist)
+	}
+}
+
+func TestLoadSdclangConfigsBadFileReportsError(t *testing.T) {
+	root := t.TempDir()
+	writeTestConfig(t, root, "sdclang.json", `not valid json`)
+
+	os.Setenv("SDCLANG_CONFIG", "sdclang.json")
+	defer os.Unsetenv("SDCLANG_CONFIG")
+
+	_, errs := loadSdclangConfigs(root)
+	if len(errs) != 1 {
+		t.Fatalf("expected exactly one error for an unparseable config, got %v", errs)
+	}
+}
+
+// withSdclangSelectionState runs f with the package-level SDClang
+// default/allowlist/blocklist set as given, restoring the previous values
+// afterward so tests don't leak state into each other.
+func withSdclangSelectionState(t *testing.T, sdclang bool, allowlist, blocklist []string, f func()) {
+	t.Helper()
+	oldSDClang, oldAllowlist, oldBlocklist := SDClang, sdclangModuleAllowlist, sdclangModuleBlocklist
+	defer func() {
+		SDClang, sdclangModuleAllowlist, sdclangModuleBlocklist = oldSDClang, oldAllowlist, oldBlocklist
+	}()
+	SDClang, sdclangModuleAllowlist, sdclangModuleBlocklist = sdclang, allowlist, blocklist
+	f()
+}
+
+func TestSDClangEnabledForModuleDefault(t *testing.T) {
+	withSdclangSelectionState(t, false, nil, nil, func() {
+		if SDClangEnabledForModule("libfoo", nil) {
+			t.Error("expected SDClang to be disabled by default when the product-wide default is off")
+		}
+	})
+	withSdclangSelectionState(t, true, nil, nil, func() {
+		if !SDClangEnabledForModule("libfoo", nil) {
+			t.Error("expected SDClang to be enabled by default when the product-wide default is on")
+		}
+	})
+}
+
+func TestSDClangEnabledForModuleAllowlist(t *testing.T) {
+	withSdclangSelectionState(t, false, []string{"libfoo"}, nil, func() {
+		if !SDClangEnabledForModule("libfoo", nil) {
+			t.Error("expected an allowlisted module to use SDClang even with the product-wide default off")
+		}
+		if SDClangEnabledForModule("libbar", nil) {
+			t.Error("expected a non-allowlisted module to fall back to the product-wide default")
+		}
+	})
+}
+
+func TestSDClangEnabledForModuleBlocklistWinsOverAllowlist(t *testing.T) {
+	withSdclangSelectionState(t, false, []string{"libfoo"}, []string{"libfoo"}, func() {
+		if SDClangEnabledForModule("libfoo", nil) {
+			t.Error("expected the blocklist to win over the allowlist for the same module")
+		}
+	})
+}
+
+func TestSDClangEnabledForModuleOverrideWinsOverAll(t *testing.T) {
+	enabled, disabled := true, false
+	withSdclangSelectionState(t, true, nil, []string{"libfoo"}, func() {
+		if !SDClangEnabledForModule("libfoo", &enabled) {
+			t.Error("expected the module's own sdclang.enabled=true to win over the blocklist")
+		}
+	})
+	withSdclangSelectionState(t, false, []string{"libfoo"}, nil, func() {
+		if SDClangEnabledForModule("libfoo", &disabled) {
+			t.Error("expected the module's own sdclang.enabled=false to win over the allowlist")
+		}
+	})
+}
+
+func TestSDClangCflagsForModuleNoOverrides(t *testing.T) {
+	cflags := []string{"-Wall", "-O2"}
+	got := SDClangCflagsForModule(cflags, SdclangProperties{})
+	if len(got) != len(cflags) {
+		t.Fatalf("expected cflags to pass through unchanged, got %v", got)
+	}
+	for i := range cflags {
+		if got[i] != cflags[i] {
+			t.Fatalf("expected cflags to pass through unchanged, got %v", got)
+		}
+	}
+}
+
+func TestSDClangCflagsForModuleExcludeAndExtra(t *testing.T) {
+	var props SdclangProperties
+	props.Sdclang.Exclude_cflags = []string{"-O2"}
+	props.Sdclang.Extra_cflags = []string{"-O3", "-flto"}
+
+	got := SDClangCflagsForModule([]string{"-Wall", "-O2"}, props)
+
+	want := []string{"-Wall", "-O3", "-flto"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}